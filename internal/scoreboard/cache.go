@@ -0,0 +1,82 @@
+package scoreboard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// userCacheEntry is what UserCache persists to disk for a single username.
+type userCacheEntry struct {
+	User         GithubUserResponse `json:"user"`
+	Found        bool               `json:"found"`
+	ETag         string             `json:"etag"`
+	LastModified string             `json:"last_modified"`
+	FetchedAt    time.Time          `json:"fetched_at"`
+}
+
+// UserCache is an on-disk, one-file-per-username cache of user profile
+// lookups, used to avoid refetching unchanged profiles on every run and to
+// make conditional requests (If-None-Match / If-Modified-Since) possible.
+type UserCache struct {
+	dir string
+}
+
+// NewUserCache returns a UserCache backed by dir, creating it if necessary.
+// A zero-value dir disables the cache: Get always misses and Put is a no-op.
+func NewUserCache(dir string) (*UserCache, error) {
+	if dir == "" {
+		return &UserCache{}, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &UserCache{dir: dir}, nil
+}
+
+func (c *UserCache) path(username string) string {
+	return filepath.Join(c.dir, username+".json")
+}
+
+// Get returns the cached entry for username, if any.
+func (c *UserCache) Get(username string) (userCacheEntry, bool) {
+	if c.dir == "" {
+		return userCacheEntry{}, false
+	}
+
+	data, err := ioutil.ReadFile(c.path(username))
+	if err != nil {
+		return userCacheEntry{}, false
+	}
+
+	var entry userCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return userCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Put stores entry for username, overwriting any previous value.
+func (c *UserCache) Put(username string, entry userCacheEntry) error {
+	if c.dir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path(username), data, 0644)
+}
+
+// Fresh reports whether entry is younger than ttl. A zero ttl means the
+// cached entry is never considered fresh on its own (but is still used to
+// drive conditional requests).
+func (entry userCacheEntry) Fresh(ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+	return time.Since(entry.FetchedAt) < ttl
+}
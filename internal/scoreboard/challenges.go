@@ -0,0 +1,365 @@
+package scoreboard
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Glob matching challenges directory.
+	// Common syntax is <anything>-<challenge_name_or_number>/author
+	challengesGlob = "./desafio-*/*"
+
+	// flat, decay and firstBloodBonus are the supported Scoring.Mode values.
+	// An empty Mode is treated as flat.
+	flat            = "flat"
+	decay           = "decay"
+	firstBloodBonus = "first_blood_bonus"
+)
+
+// PlayerChallenge holds one user/challenge pair read from the disk.
+type PlayerChallenge struct {
+	Username  string
+	Challenge string
+	// ChallengeDir is the original challenge directory name (e.g.
+	// "desafio-01"), before the "<anything>-" prefix used to derive
+	// Challenge was stripped. Kept around so Config.Points entries written
+	// against the old, full directory name still resolve.
+	ChallengeDir string
+	// Path is the on-disk directory for this submission (challenge/username
+	// under ChallengesDir). Its mtime is used as the solve timestamp by the
+	// decay and first_blood_bonus scoring modes.
+	Path string
+}
+
+// CompletedChallenge holds information about the challenges completed by
+// a user.
+type CompletedChallenge struct {
+	Name   string
+	Points int
+}
+
+// PlayerScore holds the total number of points and completed challenges for
+// one particular player.
+type PlayerScore struct {
+	Points    int
+	Completed []CompletedChallenge
+}
+
+// ScoreboardEntry holds one entry in the scoreboard. It contains all
+// information required to emit output for this player.
+type ScoreboardEntry struct {
+	Rank       int
+	GithubUser string
+	Score      PlayerScore
+	Completed  []CompletedChallenge
+	// Full info from github
+	GithubInfo GithubUserResponse
+	// True if this is the first user in a group.
+	FirstInGroup bool
+	// True if this user is the last in a group. Typically the last of a number
+	// of people with the same score.
+	LastInGroup bool
+}
+
+// Scoreboard is the top-level, template-ready result of CreateScoreboard.
+type Scoreboard struct {
+	// Overall ranks every player across all challenges.
+	Overall []ScoreboardEntry
+	// ByCategory ranks players within a single category (e.g. "crypto"),
+	// keyed on category name. A player only appears in the categories they
+	// have at least one completed challenge in, giving partial-credit
+	// visibility (e.g. "#3 in algorithms, #17 overall").
+	ByCategory map[string][]ScoreboardEntry
+	// ScoringMode echoes the Scoring.Mode used to compute the above, so the
+	// template can say how points were calculated.
+	ScoringMode string
+}
+
+// ReadChallenges reads all relevant directories under ddir and
+// return a list containing the users and challenges found.
+func ReadChallenges(ddir string) ([]PlayerChallenge, error) {
+	var ret []PlayerChallenge
+
+	dpaths, err := filepath.Glob(filepath.Join(ddir, challengesGlob))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range dpaths {
+		username, challenge, challengeDir, err := parsePath(v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, PlayerChallenge{Username: username, Challenge: challenge, ChallengeDir: challengeDir, Path: v})
+	}
+	return ret, nil
+}
+
+// MakePlayerScores generates a map of PlayerScore structures from the list of
+// player/challenges keyed on github username. Any username on the 'ignore'
+// list will be silently ignored. Uses pointsConfig and scoring to calculate
+// how much each challenge is worth in points.
+//
+// This is a two-pass computation: the first pass groups submissions by
+// challenge and orders them by solve time (the submission directory's
+// mtime), which decay and first_blood_bonus scoring need before they can
+// assign points; the second pass actually assigns points per submission.
+func MakePlayerScores(challenges []PlayerChallenge, ignore []string, pointsConfig map[string]Point, scoring ScoringConfig) (map[string]PlayerScore, error) {
+	byChallenge := map[string][]PlayerChallenge{}
+	for _, c := range challenges {
+		if inSlice(ignore, c.Username) {
+			continue
+		}
+		byChallenge[c.Challenge] = append(byChallenge[c.Challenge], c)
+	}
+
+	// First pass: order each challenge's submissions by solve time, and
+	// record how many distinct players solved it.
+	solveRank := map[PlayerChallenge]int{}
+	solveCount := map[string]int{}
+	for challenge, pcs := range byChallenge {
+		sort.Slice(pcs, func(i, j int) bool {
+			return solveTime(pcs[i]).Before(solveTime(pcs[j]))
+		})
+		solveCount[challenge] = len(pcs)
+		for i, pc := range pcs {
+			solveRank[pc] = i
+		}
+	}
+
+	// Second pass: assign points per submission.
+	scores := map[string]PlayerScore{}
+	for _, c := range challenges {
+		if inSlice(ignore, c.Username) {
+			continue
+		}
+
+		pts, err := calcScores(c, pointsConfig, scoring, solveCount[c.Challenge], solveRank[c])
+		if err != nil {
+			return nil, err
+		}
+		s, ok := scores[c.Username]
+		if !ok {
+			s = PlayerScore{}
+		}
+
+		// Add challenge to list of completed for this player
+		if !alreadyCompleted(s.Completed, c.Challenge) {
+			cc := CompletedChallenge{
+				Name:   c.Challenge,
+				Points: pts,
+			}
+			s.Completed = append(s.Completed, cc)
+		}
+		// Add total points.
+		s.Points += pts
+
+		scores[c.Username] = s
+	}
+	return scores, nil
+}
+
+// solveTime returns the timestamp used to order solves for a submission:
+// the mtime of its directory, or the zero time if it can't be stat'd.
+func solveTime(pc PlayerChallenge) time.Time {
+	info, err := os.Stat(pc.Path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// parsePath parses a path under challengesDir and returns the user, the
+// challenge id and the original challenge directory name (or error). This
+// function assumes that directories under path are laid out as
+// challenge_name/username, where challenge_name is "<anything>-<challenge
+// id>" (e.g. "desafio-01"). The leading "<anything>-" is stripped from the
+// id, so the challenge id used in most places (Config.Categories) is just
+// "01"; the untouched directory name is also returned since existing
+// Config.Points tables may still be keyed on it.
+func parsePath(path string) (string, string, string, error) {
+	elems := strings.Split(path, "/")
+	if len(elems) < 2 {
+		return "", "", "", fmt.Errorf("invalid file/dir: %q", path)
+	}
+
+	cname := elems[len(elems)-2]
+	username := elems[len(elems)-1]
+	return username, challengeID(cname), cname, nil
+}
+
+// challengeID strips the leading "<anything>-" prefix from a challenge
+// directory name, e.g. "desafio-01" becomes "01". Directory names with no
+// "-" are returned unchanged.
+func challengeID(dirname string) string {
+	if i := strings.Index(dirname, "-"); i >= 0 {
+		return dirname[i+1:]
+	}
+	return dirname
+}
+
+// calcScores returns the points earned for a single submission, under the
+// configured scoring mode. solves is the total number of distinct players
+// who solved challenge.Challenge; rank is this submission's 0-based solve
+// order within that challenge.
+func calcScores(challenge PlayerChallenge, points map[string]Point, scoring ScoringConfig, solves, rank int) (int, error) {
+	pointvalue, ok := points[challenge.Challenge]
+	if !ok {
+		// Fall back to the original, untouched directory name: older
+		// configs have Config.Points keyed on it (e.g. "desafio-01"
+		// instead of "01"), and silently breaking those isn't acceptable.
+		pointvalue, ok = points[challenge.ChallengeDir]
+	}
+	if !ok {
+		return 0, fmt.Errorf("missing points configuration for: %q", challenge.Challenge)
+	}
+	base := pointvalue.Value
+
+	switch scoring.Mode {
+	case "", flat:
+		return base, nil
+
+	case decay:
+		decayed := float64(base) * math.Exp(-scoring.DecayRate*float64(solves))
+		if decayed < scoring.MinPoints {
+			decayed = scoring.MinPoints
+		}
+		return int(math.Round(decayed)), nil
+
+	case firstBloodBonus:
+		pts := base
+		if rank < scoring.BonusSlots {
+			pts += scoring.BonusPoints
+		}
+		return pts, nil
+
+	default:
+		return 0, fmt.Errorf("unknown scoring mode: %q", scoring.Mode)
+	}
+}
+
+// inSlice returns true if a given string is inside a slice of strings.
+func inSlice(sl []string, str string) bool {
+	for _, v := range sl {
+		if str == v {
+			return true
+		}
+	}
+	return false
+}
+
+// alreadyCompleted returns true if a given challenge is already in a slice of
+// completeChallenge structs.
+func alreadyCompleted(cc []CompletedChallenge, name string) bool {
+	for _, v := range cc {
+		if name == v.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateScoreboard creates a Scoreboard, ready to be rendered by templates.
+// categories groups challenges into named categories (e.g. "crypto") so a
+// per-category subscoreboard can be produced alongside the overall one; it
+// may be nil if no categories are configured.
+func CreateScoreboard(scores map[string]PlayerScore, client RepoClient, scoringMode string, categories map[string]Category) (Scoreboard, error) {
+	var entries []ScoreboardEntry
+
+	for u, s := range scores {
+		githubInfo, ok, err := client.UserInfo(u)
+		if err != nil {
+			return Scoreboard{}, err
+		}
+		// No user on github?
+		if !ok {
+			continue
+		}
+
+		sbe := ScoreboardEntry{
+			GithubUser: u,
+			Score:      s,
+			Completed:  s.Completed,
+			GithubInfo: githubInfo,
+		}
+
+		entries = append(entries, sbe)
+	}
+
+	byCategory := map[string][]ScoreboardEntry{}
+	for name, challenges := range categories {
+		byCategory[name] = rankEntries(filterByChallenges(entries, challenges.Challenges))
+	}
+
+	return Scoreboard{
+		Overall:     rankEntries(entries),
+		ByCategory:  byCategory,
+		ScoringMode: scoringMode,
+	}, nil
+}
+
+// filterByChallenges returns a copy of entries with Score and Completed
+// restricted to the given challenge names, dropping any player left with no
+// completed challenges in that set.
+func filterByChallenges(entries []ScoreboardEntry, challenges []string) []ScoreboardEntry {
+	var filtered []ScoreboardEntry
+
+	for _, e := range entries {
+		var completed []CompletedChallenge
+		points := 0
+		for _, cc := range e.Completed {
+			if !inSlice(challenges, cc.Name) {
+				continue
+			}
+			completed = append(completed, cc)
+			points += cc.Points
+		}
+		if len(completed) == 0 {
+			continue
+		}
+
+		e.Score = PlayerScore{Points: points, Completed: completed}
+		e.Completed = completed
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// rankEntries sorts entries descending by points (ascending by username for
+// ties) and annotates Rank, FirstInGroup and LastInGroup.
+func rankEntries(entries []ScoreboardEntry) []ScoreboardEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score.Points == entries[j].Score.Points {
+			return strings.ToLower(entries[i].GithubUser) < strings.ToLower(entries[j].GithubUser)
+		}
+		return entries[i].Score.Points > entries[j].Score.Points
+	})
+
+	rank := 0
+	oldpoints := 0
+
+	for k := range entries {
+		points := entries[k].Score.Points
+		if points != oldpoints {
+			rank++
+			entries[k].FirstInGroup = true
+			if k != 0 {
+				entries[k-1].LastInGroup = true
+			}
+		}
+		entries[k].Rank = rank
+		oldpoints = points
+	}
+	// Last element is always marked as last in group.
+	if len(entries) != 0 {
+		entries[len(entries)-1].LastInGroup = true
+	}
+
+	return entries
+}
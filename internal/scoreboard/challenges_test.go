@@ -0,0 +1,88 @@
+package scoreboard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeChallengeDir creates dir/user under base, mimicking a solved
+// challenge submission on disk.
+func makeChallengeDir(t *testing.T, base, dir, user string) {
+	t.Helper()
+	path := filepath.Join(base, dir, user)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("MkdirAll(%q): %v", path, err)
+	}
+}
+
+func TestMakePlayerScoresAndCategories(t *testing.T) {
+	base := t.TempDir()
+	makeChallengeDir(t, base, "desafio-01", "alice")
+	makeChallengeDir(t, base, "desafio-02", "alice")
+	makeChallengeDir(t, base, "desafio-01", "bob")
+
+	client := NewLocalFSClient()
+	challenges, err := client.ListChallenges(base)
+	if err != nil {
+		t.Fatalf("ListChallenges: %v", err)
+	}
+
+	points := map[string]Point{
+		"01": {Value: 10},
+		"02": {Value: 20},
+	}
+	scores, err := MakePlayerScores(challenges, nil, points, ScoringConfig{})
+	if err != nil {
+		t.Fatalf("MakePlayerScores: %v", err)
+	}
+
+	if got, want := scores["alice"].Points, 30; got != want {
+		t.Errorf("alice points = %d, want %d", got, want)
+	}
+	if got, want := scores["bob"].Points, 10; got != want {
+		t.Errorf("bob points = %d, want %d", got, want)
+	}
+
+	categories := map[string]Category{
+		"crypto": {Challenges: []string{"02"}},
+	}
+	board, err := CreateScoreboard(scores, client, "", categories)
+	if err != nil {
+		t.Fatalf("CreateScoreboard: %v", err)
+	}
+
+	if len(board.Overall) != 2 {
+		t.Fatalf("len(board.Overall) = %d, want 2", len(board.Overall))
+	}
+	if board.Overall[0].GithubUser != "alice" {
+		t.Errorf("board.Overall[0].GithubUser = %q, want %q", board.Overall[0].GithubUser, "alice")
+	}
+
+	crypto := board.ByCategory["crypto"]
+	if len(crypto) != 1 || crypto[0].GithubUser != "alice" {
+		t.Errorf("ByCategory[\"crypto\"] = %+v, want just alice", crypto)
+	}
+}
+
+func TestMakePlayerScoresFallsBackToChallengeDirPoints(t *testing.T) {
+	base := t.TempDir()
+	makeChallengeDir(t, base, "desafio-01", "alice")
+
+	challenges, err := ReadChallenges(base)
+	if err != nil {
+		t.Fatalf("ReadChallenges: %v", err)
+	}
+
+	// Points keyed on the full, pre-chunk0-5 directory name should still
+	// resolve, even though Challenge is now the bare id.
+	points := map[string]Point{"desafio-01": {Value: 15}}
+	scores, err := MakePlayerScores(challenges, nil, points, ScoringConfig{})
+	if err != nil {
+		t.Fatalf("MakePlayerScores: %v", err)
+	}
+
+	if got, want := scores["alice"].Points, 15; got != want {
+		t.Errorf("alice points = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+// Package scoreboard implements challenge scanning, scoring and rendering
+// for op-scoreboard. It is kept independent from the command-line entry
+// point and the HTTP server so both can share the same core logic.
+package scoreboard
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Point holds the points required for a challenge.
+type Point struct {
+	Value int `toml:"value"`
+}
+
+// ScoringConfig selects how raw challenge points turn into the points a
+// player actually gets credited with.
+type ScoringConfig struct {
+	// Mode is one of "flat" (default), "decay" or "first_blood_bonus".
+	Mode string `toml:"mode"`
+
+	// DecayRate is "k" in points = max(MinPoints, base * exp(-k * solves)),
+	// where solves is the number of players who solved the challenge. Only
+	// used in "decay" mode.
+	DecayRate float64 `toml:"decay_rate"`
+
+	// MinPoints is the floor a decayed challenge's points never drop below.
+	// Only used in "decay" mode.
+	MinPoints float64 `toml:"min_points"`
+
+	// BonusPoints is awarded on top of the base value to each of the first
+	// BonusSlots players to solve a challenge. Only used in
+	// "first_blood_bonus" mode.
+	BonusPoints int `toml:"bonus_points"`
+
+	// BonusSlots is how many of the earliest solvers get BonusPoints. Only
+	// used in "first_blood_bonus" mode.
+	BonusSlots int `toml:"bonus_slots"`
+}
+
+// Category groups a set of challenges under a common name (e.g. "crypto",
+// "algorithms"), so a per-category subscoreboard can be produced alongside
+// the overall one.
+type Category struct {
+	Challenges []string `toml:"challenges"`
+}
+
+// Config holds the main configuration items.
+type Config struct {
+	// Directory where osprogramadores/op-website-hugo is cloned.
+	WebsiteDir string `toml:"website_dir"`
+
+	// Directory where osprogramadores/op-desafios is cloned.
+	ChallengesDir string `toml:"challenges_dir"`
+
+	// Go Template directory.
+	TemplateDir string `toml:"template_dir"`
+
+	// Points per challenge. This is a map where the key is either the bare
+	// challenge id (e.g. "01") or the full challenge directory name (e.g.
+	// "desafio-01") - both forms are accepted, so older configs keyed on the
+	// directory name keep working - and the value is the number of points
+	// this challenge is worth.
+	Points map[string]Point `toml:"points"`
+
+	// Ignore these usernames (admins, and others that don't benefit
+	// from showing in the scoreboard).
+	IgnoreUsers []string `toml:"ignore_users"`
+
+	// Directory where the awards ledger and other server state is kept.
+	// Only used when running as a long-running server.
+	DataDir string `toml:"data_dir"`
+
+	// How often the server re-scans ChallengesDir for new awards, expressed
+	// as a Go duration string (e.g. "5m"). Only used when running as a
+	// long-running server. Defaults to server.DefaultScanInterval if empty.
+	ScanInterval string `toml:"scan_interval"`
+
+	// Backend selects the RepoClient implementation used to list challenges
+	// and resolve user profiles: "github" (default), "gitlab", "gitea" or
+	// "local".
+	Backend string `toml:"backend"`
+
+	// Base URL of the Gitlab/Gitea instance. Ignored by the github and local
+	// backends.
+	BackendURL string `toml:"backend_url"`
+
+	// Directory used to cache user profile lookups between runs. Empty
+	// disables the cache. Only honored by the github backend.
+	CacheDir string `toml:"cache_dir"`
+
+	// How long a cached user profile is served without even a conditional
+	// request, expressed as a Go duration string (e.g. "24h"). Ignored if
+	// CacheDir is empty.
+	CacheTTL string `toml:"cache_ttl"`
+
+	// Scoring selects the strategy used to turn challenge point values into
+	// the points a player is actually credited with. Defaults to flat,
+	// per-challenge values when left unset.
+	Scoring ScoringConfig `toml:"scoring"`
+
+	// Categories groups challenges for per-category subscoreboards, keyed on
+	// category name (e.g. [categories.crypto]). A challenge not listed under
+	// any category is only reflected in the overall scoreboard.
+	Categories map[string]Category `toml:"categories"`
+}
+
+// ParseConfig parses the configuration string from the slice of bytes
+// containing the TOML config read from disk and performs basic sanity checking
+// of configuration items.
+func ParseConfig(r io.Reader) (Config, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Config{}, err
+	}
+
+	config := Config{}
+	if _, err := toml.Decode(string(data), &config); err != nil {
+		return Config{}, err
+	}
+
+	switch {
+	case config.WebsiteDir == "":
+		return Config{}, errors.New("WebsiteDir is empty")
+	case config.ChallengesDir == "":
+		return Config{}, errors.New("ChallengesDir is empty")
+	case config.TemplateDir == "":
+		return Config{}, errors.New("TemplateDir is empty")
+	}
+
+	return config, nil
+}
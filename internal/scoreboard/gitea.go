@@ -0,0 +1,94 @@
+package scoreboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// giteaUserResponse matches the subset of a Gitea "GET /users/search"
+// response that we care about.
+type giteaUserResponse struct {
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+}
+
+type giteaSearchResponse struct {
+	Data []giteaUserResponse `json:"data"`
+}
+
+// GiteaClient is the RepoClient implementation backed by a Gitea instance.
+// As with GithubClient and GitlabClient, challenges are read off the local
+// clone; only user profile resolution hits the network.
+type GiteaClient struct {
+	baseURL string
+	token   string
+}
+
+// NewGiteaClient returns a GiteaClient talking to baseURL, authenticating
+// with token if non-empty.
+func NewGiteaClient(baseURL, token string) *GiteaClient {
+	return &GiteaClient{baseURL: baseURL, token: token}
+}
+
+// ListChallenges implements RepoClient.
+func (c *GiteaClient) ListChallenges(dir string) ([]PlayerChallenge, error) {
+	return ReadChallenges(dir)
+}
+
+// UserInfo implements RepoClient.
+func (c *GiteaClient) UserInfo(username string) (GithubUserResponse, bool, error) {
+	// Gitea's search is a substring/relevance match over login, full name and
+	// email, not an exact lookup, so it can return other users ahead of (or
+	// instead of) the one we asked for. Pull a few candidates and pick the
+	// one whose login matches exactly.
+	u := fmt.Sprintf("%s/api/v1/users/search?q=%s&limit=10", c.baseURL, url.QueryEscape(username))
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error forming GET request for user %q: %v", username, err)
+	}
+	if c.token != "" {
+		req.Header.Add("Authorization", "token "+c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error on GET for gitea user %q: %v", username, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return GithubUserResponse{}, false, fmt.Errorf("gitea returned status %d (%s) for user %q", resp.StatusCode, resp.Status, username)
+	}
+
+	jdata, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error reading http body for user %q: %v", username, err)
+	}
+
+	var search giteaSearchResponse
+	if err := json.Unmarshal(jdata, &search); err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error decoding gitea data: %v", err)
+	}
+	for _, u0 := range search.Data {
+		if !strings.EqualFold(u0.Login, username) {
+			continue
+		}
+		return GithubUserResponse{
+			Login:     u0.Login,
+			Name:      u0.FullName,
+			AvatarURL: u0.AvatarURL,
+			HTMLURL:   u0.HTMLURL,
+		}, true, nil
+	}
+
+	// No exact login match among the candidates: the user doesn't exist on
+	// this backend.
+	return GithubUserResponse{}, false, nil
+}
@@ -0,0 +1,321 @@
+// github.go - ham fisted accesses to github /user with caching.
+package scoreboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	backoff "github.com/cenkalti/backoff/v4"
+)
+
+const (
+	// Maximum number of retries on Github API.
+	maxTries = 10
+)
+
+// GithubUserResponse holds information about a particular github user.
+type GithubUserResponse struct {
+	Login             string      `json:"login"`
+	ID                int         `json:"id"`
+	NodeID            string      `json:"node_id"`
+	AvatarURL         string      `json:"avatar_url"`
+	GravatarID        string      `json:"gravatar_id"`
+	URL               string      `json:"url"`
+	HTMLURL           string      `json:"html_url"`
+	FollowersURL      string      `json:"followers_url"`
+	FollowingURL      string      `json:"following_url"`
+	GistsURL          string      `json:"gists_url"`
+	StarredURL        string      `json:"starred_url"`
+	SubscriptionsURL  string      `json:"subscriptions_url"`
+	OrganizationsURL  string      `json:"organizations_url"`
+	ReposURL          string      `json:"repos_url"`
+	EventsURL         string      `json:"events_url"`
+	ReceivedEventsURL string      `json:"received_events_url"`
+	Type              string      `json:"type"`
+	SiteAdmin         bool        `json:"site_admin"`
+	Name              string      `json:"name"`
+	Company           interface{} `json:"company"`
+	Blog              string      `json:"blog"`
+	Location          string      `json:"location"`
+	Email             interface{} `json:"email"`
+	Hireable          interface{} `json:"hireable"`
+	Bio               interface{} `json:"bio"`
+	PublicRepos       int         `json:"public_repos"`
+	PublicGists       int         `json:"public_gists"`
+	Followers         int         `json:"followers"`
+	Following         int         `json:"following"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// githubFetchResult holds everything readFromGithub learns about a single
+// request: the body (when the user was found and the data changed), the
+// validators needed for the next conditional request, and whether the
+// server told us nothing changed.
+type githubFetchResult struct {
+	Body         []byte
+	Found        bool
+	NotModified  bool
+	ETag         string
+	LastModified string
+}
+
+const (
+	// Maximum number of times we'll wait out a primary/secondary rate limit
+	// for a single user before giving up.
+	maxRateLimitWaits = 5
+)
+
+// readFromGithub reads data for a user using the github API (v3). If etag
+// or lastModified are non-empty, they're sent as If-None-Match /
+// If-Modified-Since so an unchanged profile costs a cheap 304 instead of a
+// full response, and 304s don't count against the primary rate limit.
+func readFromGithub(username, token, etag, lastModified string) (githubFetchResult, error) {
+	log.Printf("Fetching data for github user %s", username)
+	client := &http.Client{}
+	req, err := http.NewRequest("GET", "https://api.github.com/users/"+username, nil)
+	if err != nil {
+		return githubFetchResult{}, fmt.Errorf("error forming GET request for user %q: %v", username, err)
+	}
+	if token != "" {
+		req.Header.Add("Authorization", "token "+token)
+	}
+	if etag != "" {
+		req.Header.Add("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Add("If-Modified-Since", lastModified)
+	}
+
+	// A rate limit reset can be much further out than
+	// backoff.NewExponentialBackOff's default 15m MaxElapsedTime, so it's
+	// handled in this outer loop instead of inside doGithubRequest: each
+	// rate-limited wait is followed by a *fresh* backoff attempt, rather
+	// than counting against one backoff run's elapsed-time budget.
+	for waits := 0; ; waits++ {
+		resp, err := doGithubRequest(client, req, username)
+		if err != nil {
+			return githubFetchResult{}, err
+		}
+
+		if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			drainAndClose(resp)
+			if waits >= maxRateLimitWaits {
+				return githubFetchResult{}, fmt.Errorf("github rate limit still exceeded after %d waits, user: %s", waits, username)
+			}
+			if d := rateLimitSleepDuration(resp.Header.Get("X-RateLimit-Reset")); d > 0 {
+				log.Printf("Github rate limit exceeded, sleeping %s", d)
+				time.Sleep(d)
+			}
+			continue
+		}
+
+		// Indicate invalid user (but no error) if we got a 404. This is ugly.
+		if resp.StatusCode == 404 {
+			drainAndClose(resp)
+			return githubFetchResult{}, nil
+		}
+
+		if resp.StatusCode == http.StatusNotModified {
+			drainAndClose(resp)
+			return githubFetchResult{Found: true, NotModified: true}, nil
+		}
+
+		jdata, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return githubFetchResult{}, fmt.Errorf("error reading http body for user %q: %v", username, err)
+		}
+		return githubFetchResult{
+			Body:         jdata,
+			Found:        true,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}, nil
+	}
+}
+
+// drainAndClose drains and closes resp.Body so the underlying connection can
+// be reused by client.Transport instead of being torn down.
+func drainAndClose(resp *http.Response) {
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// doGithubRequest performs req against the Github API, retrying transient
+// failures with an exponential backoff. A 404 (user not found), a 304 (not
+// modified) and a 403 rate limit response are all returned as-is, with no
+// error, for the caller to interpret; only request errors and genuinely
+// retriable statuses are retried here.
+func doGithubRequest(client *http.Client, req *http.Request, username string) (*http.Response, error) {
+	var (
+		resp *http.Response
+		try  int
+		err  error
+	)
+
+	// Returning nil will cause an exit from the Retry function. The 'err' variable
+	// indicates an error that needs to be handled outside the function.
+	backoff.Retry(func() error {
+		try++
+		err = nil
+
+		if try >= maxTries {
+			err = fmt.Errorf("maximum number of retries reached (%d), user: %s", maxTries, username)
+			return nil
+		}
+
+		resp, err = client.Do(req)
+		if err != nil {
+			m := fmt.Sprintf("error on GET for github user %q: %v (attempt %d)", username, err, try)
+			log.Print(m)
+			return errors.New(m)
+		}
+
+		// Return immediately if we can't find the github user. We set err to
+		// nil since we don't want to abort the entire program for this.
+		if resp.StatusCode == 404 {
+			log.Printf("Github user not found: %s", username)
+			return nil
+		}
+
+		// Nothing changed since our last fetch: no need to retry or read a body.
+		if resp.StatusCode == http.StatusNotModified {
+			return nil
+		}
+
+		// A secondary rate limit (or the primary one) kicks us out with a 403
+		// and a zeroed remaining count. Let the caller decide how long to
+		// sleep; that wait shouldn't eat into this backoff's elapsed-time
+		// budget.
+		if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return nil
+		}
+
+		// Retriable codes.
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			m := fmt.Sprintf("github returned status %d (%s) for user %q (attempt %d)", resp.StatusCode, resp.Status, username, try)
+			log.Print(m)
+			return errors.New(m)
+		}
+		return nil
+	}, backoff.NewExponentialBackOff())
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// rateLimitSleepDuration returns how long to sleep until the unix timestamp
+// in resetHeader, or zero if it can't be parsed.
+func rateLimitSleepDuration(resetHeader string) time.Duration {
+	reset, err := strconv.ParseInt(resetHeader, 10, 64)
+	if err != nil {
+		return 0
+	}
+	d := time.Until(time.Unix(reset, 0))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// githubUserInfo returns github information about a given username, along
+// with the fetch result: its Found field tells whether the user was found,
+// and its NotModified field whether the server answered 304, in which case
+// the returned GithubUserResponse is empty.
+func githubUserInfo(username, token, etag, lastModified string) (GithubUserResponse, githubFetchResult, error) {
+	result, err := readFromGithub(username, token, etag, lastModified)
+	if err != nil || !result.Found || result.NotModified {
+		return GithubUserResponse{}, result, err
+	}
+
+	// Unmarshal the JSON and run some basic checks.
+	var resp GithubUserResponse
+	if err := json.Unmarshal(result.Body, &resp); err != nil {
+		return GithubUserResponse{}, result, fmt.Errorf("error decoding github data: %v", err)
+	}
+	if resp.Login == "" {
+		return GithubUserResponse{}, result, fmt.Errorf("got bad json from github: %s", string(result.Body))
+	}
+
+	return resp, result, nil
+}
+
+// GithubClient is the RepoClient implementation backed by api.github.com. It
+// lists challenges straight off the local clone of the challenges repo
+// (Github gives us no better way to enumerate them) and resolves user
+// profiles through the Github REST API, using cache to avoid refetching
+// unchanged profiles.
+type GithubClient struct {
+	token string
+	cache *UserCache
+	ttl   time.Duration
+}
+
+// NewGithubClient returns a GithubClient authenticating with token (which may
+// be empty for unauthenticated, rate-limited access). cache may be nil to
+// disable on-disk caching entirely.
+func NewGithubClient(token string, cache *UserCache, ttl time.Duration) *GithubClient {
+	return &GithubClient{token: token, cache: cache, ttl: ttl}
+}
+
+// ListChallenges implements RepoClient.
+func (c *GithubClient) ListChallenges(dir string) ([]PlayerChallenge, error) {
+	return ReadChallenges(dir)
+}
+
+// UserInfo implements RepoClient. It serves straight from the on-disk cache
+// when the cached entry is younger than ttl, otherwise it makes a
+// conditional request and only pays for a full response when the profile
+// actually changed.
+func (c *GithubClient) UserInfo(username string) (GithubUserResponse, bool, error) {
+	var (
+		cached   userCacheEntry
+		hasEntry bool
+	)
+	if c.cache != nil {
+		cached, hasEntry = c.cache.Get(username)
+		if hasEntry && cached.Fresh(c.ttl) {
+			return cached.User, cached.Found, nil
+		}
+	}
+
+	etag, lastModified := "", ""
+	if hasEntry {
+		etag, lastModified = cached.ETag, cached.LastModified
+	}
+
+	resp, result, err := githubUserInfo(username, c.token, etag, lastModified)
+	if err != nil {
+		return GithubUserResponse{}, false, err
+	}
+
+	if c.cache == nil {
+		return resp, result.Found, nil
+	}
+
+	if result.NotModified {
+		cached.FetchedAt = time.Now()
+		c.cache.Put(username, cached)
+		return cached.User, cached.Found, nil
+	}
+
+	entry := userCacheEntry{
+		User:         resp,
+		Found:        result.Found,
+		ETag:         result.ETag,
+		LastModified: result.LastModified,
+		FetchedAt:    time.Now(),
+	}
+	c.cache.Put(username, entry)
+	return resp, result.Found, nil
+}
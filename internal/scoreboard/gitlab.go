@@ -0,0 +1,81 @@
+package scoreboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// gitlabUserResponse matches the subset of a Gitlab "GET /users?username="
+// response that we care about.
+type gitlabUserResponse struct {
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+	WebURL    string `json:"web_url"`
+}
+
+// GitlabClient is the RepoClient implementation backed by a Gitlab instance
+// (gitlab.com or a self-hosted one). Like GithubClient, challenges are read
+// off the local clone; only user profile resolution hits the network.
+type GitlabClient struct {
+	baseURL string
+	token   string
+}
+
+// NewGitlabClient returns a GitlabClient talking to baseURL (e.g.
+// "https://gitlab.com"), authenticating with token if non-empty.
+func NewGitlabClient(baseURL, token string) *GitlabClient {
+	return &GitlabClient{baseURL: baseURL, token: token}
+}
+
+// ListChallenges implements RepoClient.
+func (c *GitlabClient) ListChallenges(dir string) ([]PlayerChallenge, error) {
+	return ReadChallenges(dir)
+}
+
+// UserInfo implements RepoClient.
+func (c *GitlabClient) UserInfo(username string) (GithubUserResponse, bool, error) {
+	u := fmt.Sprintf("%s/api/v4/users?username=%s", c.baseURL, url.QueryEscape(username))
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error forming GET request for user %q: %v", username, err)
+	}
+	if c.token != "" {
+		req.Header.Add("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error on GET for gitlab user %q: %v", username, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return GithubUserResponse{}, false, fmt.Errorf("gitlab returned status %d (%s) for user %q", resp.StatusCode, resp.Status, username)
+	}
+
+	jdata, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error reading http body for user %q: %v", username, err)
+	}
+
+	var users []gitlabUserResponse
+	if err := json.Unmarshal(jdata, &users); err != nil {
+		return GithubUserResponse{}, false, fmt.Errorf("error decoding gitlab data: %v", err)
+	}
+	if len(users) == 0 {
+		return GithubUserResponse{}, false, nil
+	}
+
+	u0 := users[0]
+	return GithubUserResponse{
+		Login:     u0.Username,
+		Name:      u0.Name,
+		AvatarURL: u0.AvatarURL,
+		HTMLURL:   u0.WebURL,
+	}, true, nil
+}
@@ -0,0 +1,68 @@
+package scoreboard
+
+import (
+	"fmt"
+	"time"
+)
+
+// RepoClient abstracts away the repo host used to track challenges and
+// resolve user profiles, so op-scoreboard isn't hardwired to github.com. It
+// is deliberately modeled after ossf/scorecard's clients.RepoClient: one
+// small interface, one implementation per host.
+type RepoClient interface {
+	// ListChallenges returns every PlayerChallenge found under dir.
+	ListChallenges(dir string) ([]PlayerChallenge, error)
+
+	// UserInfo resolves profile information for username. The returned bool
+	// is false (with a nil error) if the user doesn't exist on this backend.
+	UserInfo(username string) (GithubUserResponse, bool, error)
+}
+
+// NewRepoClient returns the RepoClient implementation selected by
+// Config.Backend. An empty Backend defaults to "github".
+func NewRepoClient(config Config, token string) (RepoClient, error) {
+	switch config.Backend {
+	case "", "github":
+		cache, err := NewUserCache(config.CacheDir)
+		if err != nil {
+			return nil, err
+		}
+		var ttl time.Duration
+		if config.CacheTTL != "" {
+			ttl, err = time.ParseDuration(config.CacheTTL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cache_ttl %q: %v", config.CacheTTL, err)
+			}
+		}
+		return NewGithubClient(token, cache, ttl), nil
+	case "gitlab":
+		return NewGitlabClient(config.BackendURL, token), nil
+	case "gitea":
+		return NewGiteaClient(config.BackendURL, token), nil
+	case "local":
+		return NewLocalFSClient(), nil
+	default:
+		return nil, fmt.Errorf("unknown backend: %q", config.Backend)
+	}
+}
+
+// LocalFSClient lists challenges from a local directory and resolves user
+// info entirely from the username, with no network calls. It's meant for
+// testing and for mirrors that don't run any particular forge.
+type LocalFSClient struct{}
+
+// NewLocalFSClient returns a LocalFSClient.
+func NewLocalFSClient() *LocalFSClient {
+	return &LocalFSClient{}
+}
+
+// ListChallenges implements RepoClient.
+func (c *LocalFSClient) ListChallenges(dir string) ([]PlayerChallenge, error) {
+	return ReadChallenges(dir)
+}
+
+// UserInfo implements RepoClient. Every username is considered valid, with
+// Login set to username and everything else left at its zero value.
+func (c *LocalFSClient) UserInfo(username string) (GithubUserResponse, bool, error) {
+	return GithubUserResponse{Login: username}, true, nil
+}
@@ -0,0 +1,36 @@
+package scoreboard
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteTemplateFile writes a scoreboard to the default output file using a
+// specified template file.
+func WriteTemplateFile(outfile string, board Scoreboard, tfile string) error {
+	w, err := os.Create(outfile)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	return WriteTemplate(w, board, tfile)
+}
+
+// WriteTemplate writes a scoreboard to an io.Writer using a specified template
+// file.
+func WriteTemplate(w io.Writer, board Scoreboard, tfile string) error {
+	_, tbasefile := filepath.Split(tfile)
+
+	t := template.New(tbasefile)
+	t, err := t.ParseFiles(tfile)
+	if err != nil {
+		return fmt.Errorf("writeTemplate: error parsing template: %v", err)
+	}
+	if err = t.Execute(w, board); err != nil {
+		return fmt.Errorf("writeTemplate: error executing template: %v", err)
+	}
+	return nil
+}
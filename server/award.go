@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Award represents a single "user solved challenge and got points" event, as
+// recorded in the awards ledger. The on-disk format is one Award per line:
+//
+//	<unix_ts> <github_user> <challenge> <points>
+//
+// This mirrors the award log format used by MOTH-style CTF scoreboards,
+// which makes the ledger easy to tail and grep.
+type Award struct {
+	Timestamp time.Time
+	User      string
+	Challenge string
+	Points    int
+}
+
+// String renders an Award in its on-disk ledger format.
+func (a Award) String() string {
+	return fmt.Sprintf("%d %s %s %d", a.Timestamp.Unix(), a.User, a.Challenge, a.Points)
+}
+
+// ParseAward parses a single ledger line produced by Award.String.
+func ParseAward(line string) (Award, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return Award{}, fmt.Errorf("invalid award line: %q", line)
+	}
+
+	ts, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return Award{}, fmt.Errorf("invalid timestamp in award line %q: %v", line, err)
+	}
+	points, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return Award{}, fmt.Errorf("invalid points in award line %q: %v", line, err)
+	}
+
+	return Award{
+		Timestamp: time.Unix(ts, 0).UTC(),
+		User:      fields[1],
+		Challenge: fields[2],
+		Points:    points,
+	}, nil
+}
+
+// AwardList is a sortable list of Awards, ordered by Timestamp.
+type AwardList []Award
+
+func (al AwardList) Len() int      { return len(al) }
+func (al AwardList) Swap(i, j int) { al[i], al[j] = al[j], al[i] }
+func (al AwardList) Less(i, j int) bool {
+	return al[i].Timestamp.Before(al[j].Timestamp)
+}
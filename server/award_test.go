@@ -0,0 +1,55 @@
+package server
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestAwardStringParseRoundTrip(t *testing.T) {
+	want := Award{
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+		User:      "octocat",
+		Challenge: "01",
+		Points:    42,
+	}
+
+	got, err := ParseAward(want.String())
+	if err != nil {
+		t.Fatalf("ParseAward(%q) returned error: %v", want.String(), err)
+	}
+	if got != want {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAwardInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"not enough fields",
+		"notanumber octocat 01 42",
+		"1700000000 octocat 01 notanumber",
+	}
+	for _, line := range cases {
+		if _, err := ParseAward(line); err == nil {
+			t.Errorf("ParseAward(%q): got nil error, want one", line)
+		}
+	}
+}
+
+func TestAwardListSort(t *testing.T) {
+	al := AwardList{
+		{Timestamp: time.Unix(300, 0), User: "c"},
+		{Timestamp: time.Unix(100, 0), User: "a"},
+		{Timestamp: time.Unix(200, 0), User: "b"},
+	}
+
+	sort.Sort(al)
+
+	want := []string{"a", "b", "c"}
+	for i, u := range want {
+		if al[i].User != u {
+			t.Errorf("al[%d].User = %q, want %q", i, al[i].User, u)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// Ledger is an append-only log of Awards backed by a single file. Appends are
+// serialized in-process with a mutex and across processes with an flock(2)
+// advisory lock, so multiple op-scoreboard instances can share the same
+// DataDir (and so the same ledger file) without corrupting each other's
+// writes.
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLedger returns a Ledger backed by the file at path. The file is created
+// if it doesn't already exist.
+func NewLedger(path string) (*Ledger, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening ledger %q: %v", path, err)
+	}
+	f.Close()
+	return &Ledger{path: path}, nil
+}
+
+// Append adds an Award to the end of the ledger.
+func (l *Ledger) Append(a Award) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("opening ledger %q: %v", l.path, err)
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("locking ledger %q: %v", l.path, err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	if _, err := fmt.Fprintln(f, a.String()); err != nil {
+		return fmt.Errorf("writing to ledger %q: %v", l.path, err)
+	}
+	return nil
+}
+
+// ReadAll returns every Award currently in the ledger, sorted by timestamp.
+func (l *Ledger) ReadAll() (AwardList, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening ledger %q: %v", l.path, err)
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_SH); err != nil {
+		return nil, fmt.Errorf("locking ledger %q: %v", l.path, err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	var awards AwardList
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		a, err := ParseAward(line)
+		if err != nil {
+			return nil, err
+		}
+		awards = append(awards, a)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ledger %q: %v", l.path, err)
+	}
+
+	sort.Sort(awards)
+	return awards, nil
+}
@@ -0,0 +1,228 @@
+// Package server runs op-scoreboard as a long-running daemon: it
+// periodically re-scans the challenges directory, records every new
+// completion in an on-disk awards ledger, and serves a JSON API alongside
+// the existing template-based rendering.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OsProgramadores/op-scoreboard/internal/scoreboard"
+)
+
+// DefaultScanInterval is used when Config.ScanInterval is empty.
+const DefaultScanInterval = 5 * time.Minute
+
+// Server holds the periodically refreshed scoreboard state and the awards
+// ledger, and serves them through the JSON API. It does not render the
+// scoreboard template; that is done by the one-shot mode in main.
+type Server struct {
+	cfg    scoreboard.Config
+	client scoreboard.RepoClient
+
+	ledger *Ledger
+
+	mu      sync.RWMutex
+	board   scoreboard.Scoreboard
+	awarded map[string]bool // "user/challenge" pairs already in the ledger
+}
+
+// New returns a Server for the given configuration. token is the (optional)
+// API token used by the configured backend when looking up user profiles.
+func New(cfg scoreboard.Config, token string) (*Server, error) {
+	if cfg.DataDir == "" {
+		return nil, fmt.Errorf("DataDir is empty, can't run as a server")
+	}
+
+	client, err := scoreboard.NewRepoClient(cfg, token)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := NewLedger(filepath.Join(cfg.DataDir, "awards.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	awards, err := ledger.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	awarded := map[string]bool{}
+	for _, a := range awards {
+		awarded[awardKey(a.User, a.Challenge)] = true
+	}
+
+	return &Server{
+		cfg:     cfg,
+		client:  client,
+		ledger:  ledger,
+		awarded: awarded,
+	}, nil
+}
+
+// ScanInterval returns the configured scan interval, or DefaultScanInterval
+// if none was configured.
+func (s *Server) ScanInterval() time.Duration {
+	if s.cfg.ScanInterval == "" {
+		return DefaultScanInterval
+	}
+	d, err := time.ParseDuration(s.cfg.ScanInterval)
+	if err != nil {
+		return DefaultScanInterval
+	}
+	return d
+}
+
+// Run scans the challenges directory immediately, then again every
+// ScanInterval, until ctx is canceled. A canceled ctx is a normal shutdown
+// request, not a failure, and is reported as a nil error.
+//
+// Only the initial scan is fatal: it catches startup-time misconfiguration
+// (a bad ChallengesDir, a missing points entry). Once the server is up, a
+// periodic rescan failure is most likely transient (a backend rate limit, a
+// momentary filesystem hiccup), so it's logged and the previous scoreboard
+// keeps being served rather than taking down a process meant to run for
+// weeks.
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.rescan(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.ScanInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := ctx.Err(); err != nil && err != context.Canceled {
+				return err
+			}
+			return nil
+		case <-ticker.C:
+			if err := s.rescan(); err != nil {
+				log.Printf("error rescanning challenges: %v", err)
+			}
+		}
+	}
+}
+
+// rescan reads the challenges directory, appends any newly seen
+// user/challenge completions to the awards ledger, and refreshes the cached
+// scoreboard.
+func (s *Server) rescan() error {
+	challenges, err := s.client.ListChallenges(s.cfg.ChallengesDir)
+	if err != nil {
+		return err
+	}
+
+	scores, err := scoreboard.MakePlayerScores(challenges, s.cfg.IgnoreUsers, s.cfg.Points, s.cfg.Scoring)
+	if err != nil {
+		return err
+	}
+
+	board, err := scoreboard.CreateScoreboard(scores, s.client, s.cfg.Scoring.Mode, s.cfg.Categories)
+	if err != nil {
+		return err
+	}
+
+	if err := s.recordNewAwards(scores); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.board = board
+	s.mu.Unlock()
+	return nil
+}
+
+// recordNewAwards appends an Award to the ledger for every completed
+// challenge that hasn't been recorded yet.
+func (s *Server) recordNewAwards(scores map[string]scoreboard.PlayerScore) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for user, score := range scores {
+		for _, cc := range score.Completed {
+			key := awardKey(user, cc.Name)
+			if s.awarded[key] {
+				continue
+			}
+			award := Award{
+				Timestamp: time.Now(),
+				User:      user,
+				Challenge: cc.Name,
+				Points:    cc.Points,
+			}
+			if err := s.ledger.Append(award); err != nil {
+				return err
+			}
+			s.awarded[key] = true
+		}
+	}
+	return nil
+}
+
+func awardKey(user, challenge string) string {
+	return user + "/" + challenge
+}
+
+// Scoreboard returns the most recently computed scoreboard.
+func (s *Server) Scoreboard() scoreboard.Scoreboard {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.board
+}
+
+// Handler returns the HTTP handler serving the JSON API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/scoreboard", s.handleScoreboard)
+	mux.HandleFunc("/api/awards", s.handleAwards)
+	mux.HandleFunc("/api/user/", s.handleUser)
+	return mux
+}
+
+func (s *Server) handleScoreboard(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.Scoreboard())
+}
+
+func (s *Server) handleAwards(w http.ResponseWriter, r *http.Request) {
+	awards, err := s.ledger.ReadAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, awards)
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/user/")
+	if name == "" {
+		http.Error(w, "missing user name", http.StatusBadRequest)
+		return
+	}
+
+	for _, entry := range s.Scoreboard().Overall {
+		if entry.GithubUser == name {
+			writeJSON(w, entry)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}